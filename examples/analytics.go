@@ -0,0 +1,24 @@
+package main
+
+/**
+ * Analytics subclient.
+ */
+
+// AnalyticsClient groups the reporting endpoints.
+type AnalyticsClient struct {
+	core *StateSetClient
+}
+
+func (a *AnalyticsClient) GetDashboardMetrics() (map[string]interface{}, error) {
+	resp, err := a.core.doRequest("GET", "/analytics/dashboard", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]interface{}
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,93 @@
+package main
+
+/**
+ * Auth subclient.
+ */
+
+import "time"
+
+// AuthClient groups the authentication-related endpoints.
+type AuthClient struct {
+	core *StateSetClient
+}
+
+func (a *AuthClient) Login(email, password string) error {
+	body := map[string]string{
+		"email":    email,
+		"password": password,
+	}
+
+	resp, err := a.core.doRequest("POST", "/auth/login", body)
+	if err != nil {
+		return err
+	}
+
+	var authResp AuthResponse
+	if err := parseResponse(resp, &authResp); err != nil {
+		return err
+	}
+
+	a.core.setTokens(authResp.AccessToken, authResp.RefreshToken)
+
+	return nil
+}
+
+func (a *AuthClient) Register(email, password, firstName, lastName string) error {
+	body := map[string]string{
+		"email":      email,
+		"password":   password,
+		"first_name": firstName,
+		"last_name":  lastName,
+	}
+
+	resp, err := a.core.doRequest("POST", "/auth/register", body)
+	if err != nil {
+		return err
+	}
+
+	var authResp AuthResponse
+	if err := parseResponse(resp, &authResp); err != nil {
+		return err
+	}
+
+	a.core.setTokens(authResp.AccessToken, authResp.RefreshToken)
+
+	return nil
+}
+
+func (a *AuthClient) CreateAPIKey(name string, permissions []string) (map[string]string, error) {
+	expiresAt := time.Now().AddDate(1, 0, 0).Format(time.RFC3339)
+
+	body := map[string]interface{}{
+		"name":        name,
+		"permissions": permissions,
+		"expires_at":  expiresAt,
+	}
+
+	resp, err := a.core.doRequest("POST", "/auth/api-keys", body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]string
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (a *AuthClient) Logout() error {
+	resp, err := a.core.doRequest("POST", "/auth/logout", nil)
+	if err != nil {
+		return err
+	}
+
+	if err := parseResponse(resp, nil); err != nil {
+		return err
+	}
+
+	a.core.clearTokens()
+
+	return nil
+}
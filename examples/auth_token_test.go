@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func makeJWT(t *testing.T, exp time.Time) string {
+	t.Helper()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	payload, err := json.Marshal(map[string]int64{"exp": exp.Unix()})
+	if err != nil {
+		t.Fatalf("marshal claims: %v", err)
+	}
+	return header + "." + base64.RawURLEncoding.EncodeToString(payload) + ".sig"
+}
+
+func TestJWTExpiry(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	got, ok := jwtExpiry(makeJWT(t, exp))
+	if !ok {
+		t.Fatalf("jwtExpiry returned ok=false for a well-formed token")
+	}
+	if !got.Equal(exp) {
+		t.Fatalf("got exp %v, want %v", got, exp)
+	}
+}
+
+func TestJWTExpiryRejectsMalformed(t *testing.T) {
+	for _, token := range []string{"", "not-a-jwt", "a.b", "a.b.c.d"} {
+		if _, ok := jwtExpiry(token); ok {
+			t.Errorf("jwtExpiry(%q): got ok=true, want false", token)
+		}
+	}
+}
+
+func TestSignRequestMatchesHMAC(t *testing.T) {
+	c := &StateSetClient{apiKeyID: "key_1", apiKeySecret: "shh"}
+	body := []byte(`{"foo":"bar"}`)
+
+	req, err := http.NewRequest(http.MethodPost, "http://example.com/orders", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+
+	c.signRequest(req, http.MethodPost, "/orders", body)
+
+	timestamp := req.Header.Get("X-StateSet-Timestamp")
+	if timestamp == "" {
+		t.Fatalf("X-StateSet-Timestamp not set")
+	}
+
+	mac := hmac.New(sha256.New, []byte("shh"))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte(http.MethodPost))
+	mac.Write([]byte("/orders"))
+	mac.Write(body)
+	want := hex.EncodeToString(mac.Sum(nil))
+
+	if got := req.Header.Get("X-StateSet-Signature"); got != want {
+		t.Fatalf("signature = %s, want %s", got, want)
+	}
+	if got := req.Header.Get("X-StateSet-Key"); got != "key_1" {
+		t.Fatalf("X-StateSet-Key = %s, want key_1", got)
+	}
+}
+
+// TestRetryIfTokenExpiredRefreshesAndRetries drives doRequest through a
+// token_expired 401 end to end: the first attempt is rejected, the client
+// refreshes, and the retried request carries the new access token.
+func TestRetryIfTokenExpiredRefreshesAndRetries(t *testing.T) {
+	var attempts int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/refresh", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(AuthResponse{AccessToken: "new-token", RefreshToken: "new-refresh"})
+	})
+	mux.HandleFunc("/orders/ord_1/status", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"code":"token_expired","message":"expired","status":401}}`))
+			return
+		}
+
+		if got := r.Header.Get("Authorization"); got != "Bearer new-token" {
+			t.Errorf("retry used Authorization %q, want %q", got, "Bearer new-token")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Order{ID: "ord_1", Status: "cancelled"})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewStateSetClient(server.URL)
+	client.setTokens("old-token", "old-refresh")
+
+	order, err := client.Orders.UpdateStatus("ord_1", "cancelled", "")
+	if err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	if order.ID != "ord_1" {
+		t.Fatalf("got order %+v", order)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 2 {
+		t.Fatalf("handler invoked %d times, want 2 (original + retry)", got)
+	}
+	if got := client.getAccessToken(); got != "new-token" {
+		t.Fatalf("AccessToken = %q, want refreshed token", got)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+/**
+ * Batch request support for the StateSet API Go client.
+ *
+ * Batch* methods submit many operations in a single request. If the server
+ * rejects the batch endpoint (404), doBatch transparently falls back to N
+ * parallel single-item requests so callers get the same BatchResult shape
+ * either way.
+ */
+
+import (
+	"net/http"
+	"sync"
+)
+
+const defaultBatchConcurrency = 10
+
+// BatchResult is the per-item outcome of a Batch* call, index-aligned with
+// the input slice.
+type BatchResult[T any] struct {
+	Success bool
+	Result  *T
+	Error   *APIError
+}
+
+// batchRequest is the wire format submitted to a batch endpoint.
+type batchRequest[In any] struct {
+	Items []In `json:"items"`
+}
+
+// batchResponseItem is the wire format of a single result in a batch response.
+type batchResponseItem[Out any] struct {
+	Success bool `json:"success"`
+	Result  *Out `json:"result,omitempty"`
+	Error   *struct {
+		Code    string                 `json:"code"`
+		Message string                 `json:"message"`
+		Status  int                    `json:"status"`
+		Details map[string]interface{} `json:"details,omitempty"`
+	} `json:"error,omitempty"`
+}
+
+// batchResponse is the wire format of a batch endpoint's response.
+type batchResponse[Out any] struct {
+	Results []batchResponseItem[Out] `json:"results"`
+}
+
+// doBatch submits items to path as a single batch request. If the server
+// responds 404 (no batch endpoint), it falls back to calling single once
+// per item, fanned out across cfg.batchConcurrency goroutines. opts is
+// forwarded to every fanned-out call, so a caller's WithContext still
+// cancels the fallback and a WithIdempotencyKey still reaches the network.
+func doBatch[In any, Out any](core *StateSetClient, path string, items []In, single func(item In, opts ...RequestOption) (*Out, error), opts ...RequestOption) ([]BatchResult[Out], error) {
+	cfg := newRequestConfig(opts)
+
+	resp, err := core.doRequest("POST", path, batchRequest[In]{Items: items}, opts...)
+	if err == nil {
+		var parsed batchResponse[Out]
+		if err := parseResponse(resp, &parsed); err == nil {
+			results := make([]BatchResult[Out], len(parsed.Results))
+			for i, r := range parsed.Results {
+				results[i] = BatchResult[Out]{Success: r.Success, Result: r.Result}
+				if r.Error != nil {
+					results[i].Error = &APIError{
+						Code:    r.Error.Code,
+						Message: r.Error.Message,
+						Status:  r.Error.Status,
+						Details: r.Error.Details,
+					}
+				}
+			}
+			return results, nil
+		} else if apiErr, ok := AsAPIError(err); !ok || apiErr.Status != http.StatusNotFound {
+			return nil, err
+		}
+	} else if apiErr, ok := AsAPIError(err); !ok || apiErr.Status != http.StatusNotFound {
+		return nil, err
+	}
+
+	return fanOutBatch(items, single, cfg.batchConcurrency, opts...), nil
+}
+
+// fanOutBatch runs single once per item, at most concurrency at a time,
+// preserving index alignment with items. opts is passed through to every
+// call of single unchanged.
+func fanOutBatch[In any, Out any](items []In, single func(item In, opts ...RequestOption) (*Out, error), concurrency int, opts ...RequestOption) []BatchResult[Out] {
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	results := make([]BatchResult[Out], len(items))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, item := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, item In) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result, err := single(item, opts...)
+			if err != nil {
+				apiErr, ok := AsAPIError(err)
+				if !ok {
+					apiErr = &APIError{Code: "unknown", Message: err.Error()}
+				}
+				results[i] = BatchResult[Out]{Success: false, Error: apiErr}
+				return
+			}
+			results[i] = BatchResult[Out]{Success: true, Result: result}
+		}(i, item)
+	}
+
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDoBatchUsesBatchEndpointWhenAvailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/widgets/batch" {
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":[{"success":true,"result":1},{"success":true,"result":2}]}`))
+	}))
+	defer server.Close()
+
+	core := NewStateSetClient(server.URL)
+	calls := 0
+	single := func(in int, opts ...RequestOption) (*int, error) {
+		calls++
+		return &in, nil
+	}
+
+	results, err := doBatch(core, "/widgets/batch", []int{1, 2}, single)
+	if err != nil {
+		t.Fatalf("doBatch: %v", err)
+	}
+	if len(results) != 2 || !results[0].Success || *results[0].Result != 1 || !results[1].Success || *results[1].Result != 2 {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if calls != 0 {
+		t.Fatalf("single called %d times, want 0 (the batch endpoint should have handled it)", calls)
+	}
+}
+
+// TestDoBatchFallsBackAndForwardsOpts confirms that when the batch endpoint
+// 404s, doBatch's per-item fallback still receives the caller's opts (here,
+// WithContext) instead of silently dropping them.
+func TestDoBatchFallsBackAndForwardsOpts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	core := NewStateSetClient(server.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var gotCtx context.Context
+	single := func(in int, opts ...RequestOption) (*int, error) {
+		gotCtx = newRequestConfig(opts).ctx
+		return &in, nil
+	}
+
+	results, err := doBatch(core, "/widgets/batch", []int{1}, single, WithContext(ctx))
+	if err != nil {
+		t.Fatalf("doBatch: %v", err)
+	}
+	if len(results) != 1 || !results[0].Success {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if gotCtx != ctx {
+		t.Fatalf("fallback call did not receive the caller's context")
+	}
+}
+
+func TestFanOutBatchPreservesIndexAlignmentAndErrors(t *testing.T) {
+	single := func(in int, opts ...RequestOption) (*int, error) {
+		if in == 2 {
+			return nil, &APIError{Code: "boom", Message: "nope", Status: http.StatusBadRequest}
+		}
+		return &in, nil
+	}
+
+	results := fanOutBatch([]int{1, 2, 3}, single, 2)
+
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	if !results[0].Success || *results[0].Result != 1 {
+		t.Errorf("results[0] = %+v, want success with 1", results[0])
+	}
+	if results[1].Success || results[1].Error == nil || results[1].Error.Code != "boom" {
+		t.Errorf("results[1] = %+v, want failure with code boom", results[1])
+	}
+	if !results[2].Success || *results[2].Result != 3 {
+		t.Errorf("results[2] = %+v, want success with 3", results[2])
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+/**
+ * Carts subclient.
+ */
+
+import "github.com/google/uuid"
+
+// CartsClient groups the shopping cart endpoints.
+type CartsClient struct {
+	core *StateSetClient
+
+	// Provider names the payment service provider CreatePaymentIntent
+	// requests by default when the caller leaves PaymentIntentRequest.Provider
+	// empty. Swap it for your own PaymentProvider implementation to route
+	// payments through a different PSP without forking the client.
+	Provider PaymentProvider
+}
+
+func (cc *CartsClient) Create(customerID string) (*Cart, error) {
+	sessionID := uuid.New().String()
+
+	body := map[string]string{
+		"session_id": sessionID,
+	}
+	if customerID != "" {
+		body["customer_id"] = customerID
+	}
+
+	resp, err := cc.core.doRequest("POST", "/carts", body)
+	if err != nil {
+		return nil, err
+	}
+
+	var cart Cart
+	if err := parseResponse(resp, &cart); err != nil {
+		return nil, err
+	}
+
+	return &cart, nil
+}
+
+func (cc *CartsClient) Get(cartID string) (*Cart, error) {
+	resp, err := cc.core.doRequest("GET", "/carts/"+cartID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var cart Cart
+	if err := parseResponse(resp, &cart); err != nil {
+		return nil, err
+	}
+
+	return &cart, nil
+}
+
+func (cc *CartsClient) AddItem(cartID string, item CartItem) (*Cart, error) {
+	resp, err := cc.core.doRequest("POST", "/carts/"+cartID+"/items", item)
+	if err != nil {
+		return nil, err
+	}
+
+	var cart Cart
+	if err := parseResponse(resp, &cart); err != nil {
+		return nil, err
+	}
+
+	return &cart, nil
+}
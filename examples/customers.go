@@ -0,0 +1,42 @@
+package main
+
+/**
+ * Customers subclient.
+ */
+
+import "context"
+
+// CustomersClient groups the customer management endpoints.
+type CustomersClient struct {
+	core *StateSetClient
+}
+
+func (cu *CustomersClient) Create(email, firstName, lastName, phone string, opts ...RequestOption) (*Customer, error) {
+	body := map[string]string{
+		"email":      email,
+		"first_name": firstName,
+		"last_name":  lastName,
+	}
+	if phone != "" {
+		body["phone"] = phone
+	}
+
+	resp, err := cu.core.doRequest("POST", "/customers", body, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var customer Customer
+	if err := parseResponse(resp, &customer); err != nil {
+		return nil, err
+	}
+
+	return &customer, nil
+}
+
+// List returns an iterator over customers matching filter.
+func (cu *CustomersClient) List(filter ListFilter) *Iterator[Customer] {
+	return newIterator(filter, func(ctx context.Context, page, perPage int) ([]Customer, PaginationMeta, error) {
+		return fetchPage[Customer](ctx, cu.core, "/customers", filter, page, perPage)
+	})
+}
@@ -0,0 +1,96 @@
+package main
+
+/**
+ * Typed error surface for the StateSet API Go client.
+ *
+ * Every non-2xx response is turned into an *APIError so callers can branch
+ * on Code/Status instead of scraping an error string, and can read back the
+ * request ID and retry-after hint the server attaches to the response.
+ */
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// APIError represents a non-2xx response from the StateSet API.
+type APIError struct {
+	Code       string
+	Message    string
+	Status     int
+	RequestID  string
+	RetryAfter int // seconds; 0 if the response did not include Retry-After
+	Details    map[string]interface{}
+}
+
+func (e *APIError) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("API error: %s (code: %s, status: %d, request_id: %s)",
+			e.Message, e.Code, e.Status, e.RequestID)
+	}
+	return fmt.Sprintf("API error: %s (code: %s, status: %d)", e.Message, e.Code, e.Status)
+}
+
+// AsAPIError reports whether err is (or wraps) an *APIError, returning it if so.
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+// IsUnauthorized reports whether the error is a 401 response.
+func (e *APIError) IsUnauthorized() bool {
+	return e.Status == http.StatusUnauthorized
+}
+
+// IsConflict reports whether the error is a 409 response.
+func (e *APIError) IsConflict() bool {
+	return e.Status == http.StatusConflict
+}
+
+// IsRateLimited reports whether the error is a 429 response.
+func (e *APIError) IsRateLimited() bool {
+	return e.Status == http.StatusTooManyRequests
+}
+
+// IsValidation reports whether the error is a 422 response, or otherwise
+// carries the "validation_error" code.
+func (e *APIError) IsValidation() bool {
+	return e.Status == http.StatusUnprocessableEntity || e.Code == "validation_error"
+}
+
+// newAPIError builds an *APIError from an error response, attaching the
+// X-Request-ID and Retry-After headers alongside the decoded body.
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{
+		Status:    resp.StatusCode,
+		RequestID: resp.Header.Get("X-Request-ID"),
+	}
+
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			apiErr.RetryAfter = seconds
+		}
+	}
+
+	var envelope apiErrorEnvelope
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		apiErr.Code = "unknown"
+		apiErr.Message = fmt.Sprintf("request failed with status %d", resp.StatusCode)
+		return apiErr
+	}
+
+	apiErr.Code = envelope.Error.Code
+	apiErr.Message = envelope.Error.Message
+	apiErr.Details = envelope.Error.Details
+	if envelope.Error.Status != 0 {
+		apiErr.Status = envelope.Error.Status
+	}
+
+	return apiErr
+}
@@ -3,28 +3,36 @@ package main
 /**
  * StateSet API - Go Client Example
  *
- * A comprehensive Go client for the StateSet API demonstrating:
- * - Authentication (JWT & API Keys)
- * - Order management
- * - Inventory operations
- * - Shopping cart & checkout
- * - Returns & shipments
- * - Customer management
- * - Analytics
+ * A Go client for the StateSet API demonstrating:
+ * - Authentication (JWT & API Keys) via client.Auth / client.UseAPIKey
+ * - Order management via client.Orders
+ * - Inventory operations via client.Inventory, including BatchAdjust
+ * - Shopping cart, payment, & checkout via client.Carts
+ * - Returns & shipments via client.Returns / client.Shipments
+ * - Customer management via client.Customers
+ * - Analytics via client.Analytics
+ * - Request options (WithIdempotencyKey) and typed errors (AsAPIError)
+ *
+ * Webhook deliveries are handled by the separate webhook package; see
+ * webhook/webhook_test.go for a runnable example, since it is a separate
+ * package and isn't imported here.
  *
  * Installation:
  * go get github.com/google/uuid
  *
  * Usage:
- * go run go-example.go
+ * go run .
  */
 
 import (
 	"bytes"
+	"container/list"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -42,14 +50,14 @@ type AuthResponse struct {
 }
 
 type Order struct {
-	ID           string      `json:"id"`
-	CustomerID   string      `json:"customer_id"`
-	Status       string      `json:"status"`
-	TotalAmount  float64     `json:"total_amount"`
-	Currency     string      `json:"currency"`
-	Items        []OrderItem `json:"items"`
-	CreatedAt    string      `json:"created_at"`
-	UpdatedAt    string      `json:"updated_at"`
+	ID          string      `json:"id"`
+	CustomerID  string      `json:"customer_id"`
+	Status      string      `json:"status"`
+	TotalAmount float64     `json:"total_amount"`
+	Currency    string      `json:"currency"`
+	Items       []OrderItem `json:"items"`
+	CreatedAt   string      `json:"created_at"`
+	UpdatedAt   string      `json:"updated_at"`
 }
 
 type OrderItem struct {
@@ -61,12 +69,12 @@ type OrderItem struct {
 }
 
 type InventoryItem struct {
-	ID                string  `json:"id"`
-	SKU               string  `json:"sku"`
-	LocationID        string  `json:"location_id"`
-	QuantityOnHand    int     `json:"quantity_on_hand"`
-	QuantityAllocated int     `json:"quantity_allocated"`
-	QuantityAvailable int     `json:"quantity_available"`
+	ID                string `json:"id"`
+	SKU               string `json:"sku"`
+	LocationID        string `json:"location_id"`
+	QuantityOnHand    int    `json:"quantity_on_hand"`
+	QuantityAllocated int    `json:"quantity_allocated"`
+	QuantityAvailable int    `json:"quantity_available"`
 }
 
 type Cart struct {
@@ -122,17 +130,9 @@ type ReturnItem struct {
 	Description *string `json:"description,omitempty"`
 }
 
-type PaginatedResponse struct {
-	Data       interface{} `json:"data"`
-	Pagination struct {
-		Page       int `json:"page"`
-		PerPage    int `json:"per_page"`
-		Total      int `json:"total"`
-		TotalPages int `json:"total_pages"`
-	} `json:"pagination"`
-}
-
-type APIError struct {
+// apiErrorEnvelope is the wire format of an error response body. It is
+// decoded internally and converted into an *APIError (see errors.go).
+type apiErrorEnvelope struct {
 	Error struct {
 		Code    string                 `json:"code"`
 		Message string                 `json:"message"`
@@ -145,522 +145,310 @@ type APIError struct {
 // StateSet API Client
 // ============================================================================
 
+// StateSetClient is the shared core that every resource subclient (Orders,
+// Inventory, Carts, ...) sends its requests through.
 type StateSetClient struct {
 	BaseURL      string
 	HTTPClient   *http.Client
 	AccessToken  string
 	RefreshToken string
+
+	idempotency *idempotencyCache
+
+	tokenMu      sync.Mutex
+	authMode     authMode
+	apiKeyID     string
+	apiKeySecret string
+
+	Auth      *AuthClient
+	Orders    *OrdersClient
+	Inventory *InventoryClient
+	Carts     *CartsClient
+	Customers *CustomersClient
+	Returns   *ReturnsClient
+	Shipments *ShipmentsClient
+	Analytics *AnalyticsClient
+}
+
+// cachedResponse is the recorded outcome of a successful idempotent request,
+// replayed verbatim when the same Idempotency-Key is seen again.
+type cachedResponse struct {
+	statusCode int
+	header     http.Header
+	body       []byte
 }
 
-// NewStateSetClient creates a new API client
+// defaultIdempotencyCacheCapacity bounds idempotencyCache the same way
+// webhook.lruSeenStore bounds its own dedup table: a long-lived process
+// mints a fresh Idempotency-Key per business operation, so without a cap
+// the cache would grow forever.
+const defaultIdempotencyCacheCapacity = 10_000
+
+// NewStateSetClient creates a new API client and wires up its resource
+// subclients (each just a thin wrapper holding a back-reference to the
+// shared core, so building them costs nothing until they're used).
 func NewStateSetClient(baseURL string) *StateSetClient {
 	if baseURL == "" {
 		baseURL = "http://localhost:8080/api/v1"
 	}
 
-	return &StateSetClient{
+	c := &StateSetClient{
 		BaseURL: baseURL,
 		HTTPClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-	}
-}
-
-// doRequest performs an HTTP request with authentication
-func (c *StateSetClient) doRequest(method, path string, body interface{}) (*http.Response, error) {
-	var reqBody io.Reader
-	if body != nil {
-		jsonData, err := json.Marshal(body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %w", err)
-		}
-		reqBody = bytes.NewBuffer(jsonData)
-	}
-
-	req, err := http.NewRequest(method, c.BaseURL+path, reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	if c.AccessToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.AccessToken)
-	}
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-
-	return resp, nil
-}
-
-// parseResponse parses the response body into the target struct
-func parseResponse(resp *http.Response, target interface{}) error {
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 {
-		var apiErr APIError
-		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil {
-			return fmt.Errorf("request failed with status %d", resp.StatusCode)
-		}
-		return fmt.Errorf("API error: %s (code: %s, status: %d)",
-			apiErr.Error.Message, apiErr.Error.Code, apiErr.Error.Status)
-	}
-
-	if target != nil {
-		if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
-			return fmt.Errorf("failed to decode response: %w", err)
+		idempotency: newIdempotencyCache(defaultIdempotencyCacheCapacity),
+	}
+
+	c.Auth = &AuthClient{core: c}
+	c.Orders = &OrdersClient{core: c}
+	c.Inventory = &InventoryClient{core: c}
+	c.Carts = &CartsClient{core: c, Provider: StripeProvider{}}
+	c.Customers = &CustomersClient{core: c}
+	c.Returns = &ReturnsClient{core: c}
+	c.Shipments = &ShipmentsClient{core: c}
+	c.Analytics = &AnalyticsClient{core: c}
+
+	return c
+}
+
+// doRequest performs an HTTP request with authentication. Mutating requests
+// (POST/PUT/DELETE) may pass WithIdempotencyKey, WithHeader, WithTimeout, or
+// WithContext to customize the call without touching the shared client.
+//
+// In bearer-token mode, a JWT within 60s of expiry is refreshed before the
+// request is sent, and a 401 carrying code "token_expired" triggers one
+// refresh-and-retry of the original request.
+func (c *StateSetClient) doRequest(method, path string, body interface{}, opts ...RequestOption) (*http.Response, error) {
+	cfg := newRequestConfig(opts)
+
+	if cfg.idempotencyKey != "" {
+		if cached := c.lookupIdempotentResponse(cfg.idempotencyKey); cached != nil {
+			return cached, nil
 		}
 	}
 
-	return nil
-}
-
-// ==========================================================================
-// Authentication
-// ==========================================================================
-
-func (c *StateSetClient) Login(email, password string) error {
-	body := map[string]string{
-		"email":    email,
-		"password": password,
-	}
-
-	resp, err := c.doRequest("POST", "/auth/login", body)
-	if err != nil {
-		return err
+	ctx := cfg.ctx
+	if ctx == nil {
+		ctx = context.Background()
 	}
-
-	var authResp AuthResponse
-	if err := parseResponse(resp, &authResp); err != nil {
-		return err
+	if cfg.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.timeout)
+		defer cancel()
 	}
 
-	c.AccessToken = authResp.AccessToken
-	c.RefreshToken = authResp.RefreshToken
-
-	return nil
-}
-
-func (c *StateSetClient) Register(email, password, firstName, lastName string) error {
-	body := map[string]string{
-		"email":      email,
-		"password":   password,
-		"first_name": firstName,
-		"last_name":  lastName,
-	}
-
-	resp, err := c.doRequest("POST", "/auth/register", body)
-	if err != nil {
-		return err
-	}
-
-	var authResp AuthResponse
-	if err := parseResponse(resp, &authResp); err != nil {
-		return err
-	}
-
-	c.AccessToken = authResp.AccessToken
-	c.RefreshToken = authResp.RefreshToken
-
-	return nil
-}
-
-func (c *StateSetClient) CreateAPIKey(name string, permissions []string) (map[string]string, error) {
-	expiresAt := time.Now().AddDate(1, 0, 0).Format(time.RFC3339)
-
-	body := map[string]interface{}{
-		"name":        name,
-		"permissions": permissions,
-		"expires_at":  expiresAt,
+	if c.authMode == authModeBearer {
+		c.refreshIfExpiringSoon()
 	}
 
-	resp, err := c.doRequest("POST", "/auth/api-keys", body)
+	resp, err := c.send(ctx, method, path, body, cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	var result map[string]string
-	if err := parseResponse(resp, &result); err != nil {
-		return nil, err
-	}
-
-	return result, nil
-}
-
-func (c *StateSetClient) Logout() error {
-	resp, err := c.doRequest("POST", "/auth/logout", nil)
-	if err != nil {
-		return err
-	}
-
-	if err := parseResponse(resp, nil); err != nil {
-		return err
-	}
-
-	c.AccessToken = ""
-	c.RefreshToken = ""
-
-	return nil
-}
-
-// ==========================================================================
-// Orders
-// ==========================================================================
-
-func (c *StateSetClient) CreateOrder(customerID string, items []OrderItem) (*Order, error) {
-	body := map[string]interface{}{
-		"customer_id": customerID,
-		"items":       items,
-	}
-
-	resp, err := c.doRequest("POST", "/orders", body)
-	if err != nil {
-		return nil, err
+	if c.authMode == authModeBearer && resp.StatusCode == http.StatusUnauthorized {
+		resp, err = c.retryIfTokenExpired(ctx, method, path, body, cfg, resp)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	var order Order
-	if err := parseResponse(resp, &order); err != nil {
-		return nil, err
+	if cfg.idempotencyKey != "" && resp.StatusCode < 400 {
+		resp = c.storeIdempotentResponse(cfg.idempotencyKey, resp)
 	}
 
-	return &order, nil
+	return resp, nil
 }
 
-func (c *StateSetClient) ListOrders(page, limit int) (*PaginatedResponse, error) {
-	path := fmt.Sprintf("/orders?page=%d&limit=%d", page, limit)
-
-	resp, err := c.doRequest("GET", path, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	var result PaginatedResponse
-	if err := parseResponse(resp, &result); err != nil {
-		return nil, err
+// send marshals body, signs or authenticates the request according to the
+// client's auth mode, and performs it.
+func (c *StateSetClient) send(ctx context.Context, method, path string, body interface{}, cfg *requestConfig) (*http.Response, error) {
+	var jsonData []byte
+	if body != nil {
+		var err error
+		jsonData, err = json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
 	}
 
-	return &result, nil
-}
-
-func (c *StateSetClient) GetOrder(orderID string) (*Order, error) {
-	resp, err := c.doRequest("GET", "/orders/"+orderID, nil)
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, bytes.NewReader(jsonData))
 	if err != nil {
-		return nil, err
-	}
-
-	var order Order
-	if err := parseResponse(resp, &order); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	return &order, nil
-}
-
-func (c *StateSetClient) UpdateOrderStatus(orderID, status, notes string) (*Order, error) {
-	body := map[string]string{
-		"status": status,
-		"notes":  notes,
+	req.Header.Set("Content-Type", "application/json")
+	switch c.authMode {
+	case authModeAPIKey:
+		c.signRequest(req, method, path, jsonData)
+	default:
+		if accessToken := c.getAccessToken(); accessToken != "" {
+			req.Header.Set("Authorization", "Bearer "+accessToken)
+		}
 	}
-
-	resp, err := c.doRequest("PUT", "/orders/"+orderID+"/status", body)
-	if err != nil {
-		return nil, err
+	if cfg.idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", cfg.idempotencyKey)
 	}
-
-	var order Order
-	if err := parseResponse(resp, &order); err != nil {
-		return nil, err
+	for key, value := range cfg.headers {
+		req.Header.Set(key, value)
 	}
 
-	return &order, nil
-}
-
-func (c *StateSetClient) CancelOrder(orderID, reason string) (*Order, error) {
-	body := map[string]string{
-		"reason": reason,
+	httpClient := c.HTTPClient
+	if cfg.timeout > 0 {
+		perCall := *c.HTTPClient
+		perCall.Timeout = cfg.timeout
+		httpClient = &perCall
 	}
 
-	resp, err := c.doRequest("POST", "/orders/"+orderID+"/cancel", body)
+	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, err
-	}
-
-	var order Order
-	if err := parseResponse(resp, &order); err != nil {
-		return nil, err
-	}
-
-	return &order, nil
-}
-
-// ==========================================================================
-// Inventory
-// ==========================================================================
-
-func (c *StateSetClient) ListInventory(page, limit int) (*PaginatedResponse, error) {
-	path := fmt.Sprintf("/inventory?page=%d&limit=%d", page, limit)
-
-	resp, err := c.doRequest("GET", path, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	var result PaginatedResponse
-	if err := parseResponse(resp, &result); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
-	return &result, nil
+	return resp, nil
 }
 
-func (c *StateSetClient) GetLowStockItems() ([]InventoryItem, error) {
-	resp, err := c.doRequest("GET", "/inventory/low-stock", nil)
-	if err != nil {
-		return nil, err
+// retryIfTokenExpired inspects a 401 response and, if it carries code
+// "token_expired", refreshes the access token and retries the request
+// exactly once. Any other 401 (or a failed refresh) is returned unchanged.
+func (c *StateSetClient) retryIfTokenExpired(ctx context.Context, method, path string, body interface{}, cfg *requestConfig, resp *http.Response) (*http.Response, error) {
+	data, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if readErr != nil {
+		return resp, nil
 	}
 
-	var result struct {
-		Data []InventoryItem `json:"data"`
-	}
-	if err := parseResponse(resp, &result); err != nil {
-		return nil, err
+	restored := func() *http.Response {
+		return &http.Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: io.NopCloser(bytes.NewReader(data))}
 	}
 
-	return result.Data, nil
-}
-
-func (c *StateSetClient) ReserveInventory(inventoryID string, quantity int, orderID string) error {
-	expiresAt := time.Now().Add(24 * time.Hour).Format(time.RFC3339)
-
-	body := map[string]interface{}{
-		"quantity":   quantity,
-		"order_id":   orderID,
-		"expires_at": expiresAt,
+	var envelope apiErrorEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil || envelope.Error.Code != "token_expired" {
+		return restored(), nil
 	}
 
-	resp, err := c.doRequest("POST", "/inventory/"+inventoryID+"/reserve", body)
-	if err != nil {
-		return err
+	if err := c.refreshAccessToken(); err != nil {
+		return restored(), nil
 	}
 
-	return parseResponse(resp, nil)
+	return c.send(ctx, method, path, body, cfg)
 }
 
-// ==========================================================================
-// Shopping Cart
-// ==========================================================================
-
-func (c *StateSetClient) CreateCart(customerID string) (*Cart, error) {
-	sessionID := uuid.New().String()
-
-	body := map[string]string{
-		"session_id": sessionID,
-	}
-	if customerID != "" {
-		body["customer_id"] = customerID
+// lookupIdempotentResponse returns a fresh *http.Response replaying a
+// previously cached success for key, or nil on a cache miss.
+func (c *StateSetClient) lookupIdempotentResponse(key string) *http.Response {
+	cached, ok := c.idempotency.get(key)
+	if !ok {
+		return nil
 	}
 
-	resp, err := c.doRequest("POST", "/carts", body)
-	if err != nil {
-		return nil, err
+	return &http.Response{
+		StatusCode: cached.statusCode,
+		Header:     cached.header.Clone(),
+		Body:       io.NopCloser(bytes.NewReader(cached.body)),
 	}
-
-	var cart Cart
-	if err := parseResponse(resp, &cart); err != nil {
-		return nil, err
-	}
-
-	return &cart, nil
 }
 
-func (c *StateSetClient) GetCart(cartID string) (*Cart, error) {
-	resp, err := c.doRequest("GET", "/carts/"+cartID, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	var cart Cart
-	if err := parseResponse(resp, &cart); err != nil {
-		return nil, err
-	}
-
-	return &cart, nil
-}
+// storeIdempotentResponse records resp's body under key and returns a fresh
+// *http.Response so the original body can still be read by the caller.
+func (c *StateSetClient) storeIdempotentResponse(key string, resp *http.Response) *http.Response {
+	defer resp.Body.Close()
 
-func (c *StateSetClient) AddItemToCart(cartID string, item CartItem) (*Cart, error) {
-	resp, err := c.doRequest("POST", "/carts/"+cartID+"/items", item)
+	data, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		resp.Body = io.NopCloser(bytes.NewReader(nil))
+		return resp
 	}
 
-	var cart Cart
-	if err := parseResponse(resp, &cart); err != nil {
-		return nil, err
-	}
+	c.idempotency.put(key, &cachedResponse{
+		statusCode: resp.StatusCode,
+		header:     resp.Header.Clone(),
+		body:       data,
+	})
 
-	return &cart, nil
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	return resp
 }
 
-// ==========================================================================
-// Customers
-// ==========================================================================
-
-func (c *StateSetClient) CreateCustomer(email, firstName, lastName, phone string) (*Customer, error) {
-	body := map[string]string{
-		"email":      email,
-		"first_name": firstName,
-		"last_name":  lastName,
-	}
-	if phone != "" {
-		body["phone"] = phone
-	}
-
-	resp, err := c.doRequest("POST", "/customers", body)
-	if err != nil {
-		return nil, err
-	}
-
-	var customer Customer
-	if err := parseResponse(resp, &customer); err != nil {
-		return nil, err
-	}
-
-	return &customer, nil
+// idempotencyCache is a bounded, LRU-evicting cache of cachedResponses
+// keyed by Idempotency-Key, safe for concurrent use. It mirrors
+// webhook.lruSeenStore's eviction scheme.
+type idempotencyCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	index    map[string]*list.Element
 }
 
-func (c *StateSetClient) ListCustomers(page, limit int) (*PaginatedResponse, error) {
-	path := fmt.Sprintf("/customers?page=%d&limit=%d", page, limit)
-
-	resp, err := c.doRequest("GET", path, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	var result PaginatedResponse
-	if err := parseResponse(resp, &result); err != nil {
-		return nil, err
-	}
-
-	return &result, nil
+type idempotencyEntry struct {
+	key      string
+	response *cachedResponse
 }
 
-// ==========================================================================
-// Returns
-// ==========================================================================
-
-func (c *StateSetClient) CreateReturn(orderID string, items []ReturnItem, notes string) (*Return, error) {
-	body := map[string]interface{}{
-		"order_id": orderID,
-		"items":    items,
+func newIdempotencyCache(capacity int) *idempotencyCache {
+	return &idempotencyCache{
+		capacity: capacity,
+		order:    list.New(),
+		index:    make(map[string]*list.Element),
 	}
-	if notes != "" {
-		body["customer_notes"] = notes
-	}
-
-	resp, err := c.doRequest("POST", "/returns", body)
-	if err != nil {
-		return nil, err
-	}
-
-	var returnObj Return
-	if err := parseResponse(resp, &returnObj); err != nil {
-		return nil, err
-	}
-
-	return &returnObj, nil
 }
 
-func (c *StateSetClient) ApproveReturn(returnID string) (*Return, error) {
-	resp, err := c.doRequest("POST", "/returns/"+returnID+"/approve", nil)
-	if err != nil {
-		return nil, err
-	}
+func (c *idempotencyCache) get(key string) (*cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	var returnObj Return
-	if err := parseResponse(resp, &returnObj); err != nil {
-		return nil, err
+	elem, ok := c.index[key]
+	if !ok {
+		return nil, false
 	}
-
-	return &returnObj, nil
+	c.order.MoveToFront(elem)
+	return elem.Value.(*idempotencyEntry).response, true
 }
 
-// ==========================================================================
-// Shipments
-// ==========================================================================
+func (c *idempotencyCache) put(key string, resp *cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-func (c *StateSetClient) CreateShipment(orderID, carrier, serviceLevel string) (*Shipment, error) {
-	body := map[string]string{
-		"order_id":      orderID,
-		"carrier":       carrier,
-		"service_level": serviceLevel,
+	if elem, ok := c.index[key]; ok {
+		c.order.MoveToFront(elem)
+		elem.Value.(*idempotencyEntry).response = resp
+		return
 	}
 
-	resp, err := c.doRequest("POST", "/shipments", body)
-	if err != nil {
-		return nil, err
-	}
+	elem := c.order.PushFront(&idempotencyEntry{key: key, response: resp})
+	c.index[key] = elem
 
-	var shipment Shipment
-	if err := parseResponse(resp, &shipment); err != nil {
-		return nil, err
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.index, oldest.Value.(*idempotencyEntry).key)
+		}
 	}
-
-	return &shipment, nil
 }
 
-func (c *StateSetClient) MarkAsShipped(shipmentID, trackingNumber string) (*Shipment, error) {
-	body := map[string]string{
-		"tracking_number": trackingNumber,
-		"shipped_at":      time.Now().Format(time.RFC3339),
-	}
-
-	resp, err := c.doRequest("POST", "/shipments/"+shipmentID+"/ship", body)
-	if err != nil {
-		return nil, err
-	}
-
-	var shipment Shipment
-	if err := parseResponse(resp, &shipment); err != nil {
-		return nil, err
-	}
-
-	return &shipment, nil
-}
+// parseResponse parses the response body into the target struct
+func parseResponse(resp *http.Response, target interface{}) error {
+	defer resp.Body.Close()
 
-func (c *StateSetClient) TrackShipment(trackingNumber string) (map[string]interface{}, error) {
-	resp, err := c.doRequest("GET", "/shipments/track/"+trackingNumber, nil)
-	if err != nil {
-		return nil, err
+	if resp.StatusCode >= 400 {
+		return newAPIError(resp)
 	}
 
-	var result map[string]interface{}
-	if err := parseResponse(resp, &result); err != nil {
-		return nil, err
+	if target != nil {
+		if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
 	}
 
-	return result, nil
+	return nil
 }
 
 // ==========================================================================
-// Analytics
+// Health
 // ==========================================================================
 
-func (c *StateSetClient) GetDashboardMetrics() (map[string]interface{}, error) {
-	resp, err := c.doRequest("GET", "/analytics/dashboard", nil)
-	if err != nil {
-		return nil, err
-	}
-
-	var result map[string]interface{}
-	if err := parseResponse(resp, &result); err != nil {
-		return nil, err
-	}
-
-	return result, nil
-}
-
 func (c *StateSetClient) GetHealth() (map[string]interface{}, error) {
 	resp, err := c.doRequest("GET", "/health", nil)
 	if err != nil {
@@ -686,7 +474,7 @@ func main() {
 
 	// 1. Authentication
 	fmt.Println("1️⃣  Authenticating...")
-	if err := client.Login("admin@stateset.com", "your-password"); err != nil {
+	if err := client.Auth.Login("admin@stateset.com", "your-password"); err != nil {
 		fmt.Printf("❌ Login failed: %v\n", err)
 		return
 	}
@@ -703,7 +491,7 @@ func main() {
 
 	// 3. Create a customer
 	fmt.Println("3️⃣  Creating customer...")
-	customer, err := client.CreateCustomer(
+	customer, err := client.Customers.Create(
 		fmt.Sprintf("test-%d@example.com", time.Now().Unix()),
 		"John",
 		"Doe",
@@ -717,7 +505,7 @@ func main() {
 
 	// 4. Create a shopping cart
 	fmt.Println("4️⃣  Creating shopping cart...")
-	cart, err := client.CreateCart(customer.ID)
+	cart, err := client.Carts.Create(customer.ID)
 	if err != nil {
 		fmt.Printf("❌ Cart creation failed: %v\n", err)
 	} else {
@@ -725,7 +513,7 @@ func main() {
 
 		// 5. Add items to cart
 		fmt.Println("5️⃣  Adding items to cart...")
-		_, err = client.AddItemToCart(cart.ID, CartItem{
+		_, err = client.Carts.AddItem(cart.ID, CartItem{
 			ProductID: uuid.New().String(),
 			SKU:       "WIDGET-001",
 			Quantity:  2,
@@ -741,16 +529,16 @@ func main() {
 
 	// 6. List orders
 	fmt.Println("6️⃣  Listing orders...")
-	orders, err := client.ListOrders(1, 5)
+	orders, err := client.Orders.List(ListFilter{PerPage: 5}).Collect(context.Background(), 5)
 	if err != nil {
 		fmt.Printf("❌ Failed to list orders: %v\n", err)
 	} else {
-		fmt.Printf("✅ Orders retrieved\n\n")
+		fmt.Printf("✅ Orders retrieved: %d\n\n", len(orders))
 	}
 
 	// 7. Check low stock items
 	fmt.Println("7️⃣  Checking low stock items...")
-	lowStock, err := client.GetLowStockItems()
+	lowStock, err := client.Inventory.GetLowStockItems()
 	if err != nil {
 		fmt.Printf("❌ Failed to get low stock: %v\n", err)
 	} else {
@@ -759,7 +547,7 @@ func main() {
 
 	// 8. Get dashboard metrics
 	fmt.Println("8️⃣  Fetching analytics...")
-	dashboard, err := client.GetDashboardMetrics()
+	dashboard, err := client.Analytics.GetDashboardMetrics()
 	if err != nil {
 		fmt.Printf("❌ Failed to get dashboard: %v\n", err)
 	} else {
@@ -769,7 +557,7 @@ func main() {
 
 	// 9. Create API key
 	fmt.Println("9️⃣  Creating API key...")
-	apiKey, err := client.CreateAPIKey("Test API Key", []string{"orders:read", "inventory:read"})
+	apiKey, err := client.Auth.CreateAPIKey("Test API Key", []string{"orders:read", "inventory:read"})
 	if err != nil {
 		fmt.Printf("❌ Failed to create API key: %v\n", err)
 	} else {
@@ -778,6 +566,62 @@ func main() {
 			key = key[:20] + "..."
 		}
 		fmt.Printf("✅ API Key created: %s\n\n", key)
+
+		// 10. Switch to HMAC request signing for the rest of the session, as
+		// a server-to-server integration would.
+		fmt.Println("🔟 Switching to API-key auth...")
+		client.UseAPIKey(apiKey["key_id"], apiKey["secret"])
+		fmt.Println("✅ Now signing requests with the API key")
+	}
+
+	if cart != nil {
+		// 11. Pay for the cart and check out. The idempotency key is
+		// resolved once, so retrying Checkout after a network blip with the
+		// same opt replays the cached response instead of double-charging.
+		fmt.Println("1️⃣1️⃣  Paying for cart...")
+		checkoutKey := WithIdempotencyKey()
+		intent, err := client.Carts.CreatePaymentIntent(cart.ID, PaymentIntentRequest{
+			Amount:   cart.Total,
+			Currency: "usd",
+		})
+		if err != nil {
+			fmt.Printf("❌ Failed to create payment intent: %v\n", err)
+		} else if _, err := client.Carts.ConfirmPayment(cart.ID, "pm_card_visa"); err != nil {
+			fmt.Printf("❌ Failed to confirm payment: %v\n", err)
+		} else {
+			order, err := client.Carts.Checkout(cart.ID, CheckoutRequest{
+				PaymentIntentID: intent.ID,
+				ShippingAddress: Address{
+					Line1:      "1 Main St",
+					City:       "Austin",
+					State:      "TX",
+					PostalCode: "78701",
+					Country:    "US",
+				},
+			}, checkoutKey)
+			if err != nil {
+				if apiErr, ok := AsAPIError(err); ok && apiErr.IsConflict() {
+					fmt.Printf("⚠️  Checkout already completed: %v\n\n", apiErr)
+				} else {
+					fmt.Printf("❌ Checkout failed: %v\n", err)
+				}
+			} else {
+				fmt.Printf("✅ Order placed: %s\n\n", order.ID)
+			}
+		}
+	}
+
+	// 12. Adjust inventory for several SKUs in one call, falling back to
+	// parallel single-item requests if the server doesn't support batching.
+	fmt.Println("1️⃣2️⃣  Batch-adjusting inventory...")
+	adjustResults, err := client.Inventory.BatchAdjust([]InventoryAdjustment{
+		{InventoryID: "inv_1", Delta: -2, Reason: "sale"},
+		{InventoryID: "inv_2", Delta: 10, Reason: "restock"},
+	})
+	if err != nil {
+		fmt.Printf("❌ Batch adjust failed: %v\n", err)
+	} else {
+		fmt.Printf("✅ Batch-adjusted %d inventory items\n\n", len(adjustResults))
 	}
 
 	fmt.Println("✨ All examples completed successfully!")
@@ -0,0 +1,117 @@
+package main
+
+/**
+ * Inventory subclient.
+ */
+
+import (
+	"context"
+	"time"
+)
+
+// InventoryClient groups the inventory management endpoints.
+type InventoryClient struct {
+	core *StateSetClient
+}
+
+// InventoryAdjustment is a single item submitted to BatchAdjust.
+type InventoryAdjustment struct {
+	InventoryID string `json:"inventory_id"`
+	Delta       int    `json:"delta"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// Reservation is a single item submitted to BatchReserve.
+type Reservation struct {
+	InventoryID string `json:"inventory_id"`
+	Quantity    int    `json:"quantity"`
+	OrderID     string `json:"order_id"`
+}
+
+// ReservationResult is the per-item outcome of a BatchReserve call.
+type ReservationResult struct {
+	InventoryID string `json:"inventory_id"`
+	Quantity    int    `json:"quantity"`
+	OrderID     string `json:"order_id"`
+}
+
+// List returns an iterator over inventory items matching filter.
+func (i *InventoryClient) List(filter ListFilter) *Iterator[InventoryItem] {
+	return newIterator(filter, func(ctx context.Context, page, perPage int) ([]InventoryItem, PaginationMeta, error) {
+		return fetchPage[InventoryItem](ctx, i.core, "/inventory", filter, page, perPage)
+	})
+}
+
+func (i *InventoryClient) GetLowStockItems() ([]InventoryItem, error) {
+	resp, err := i.core.doRequest("GET", "/inventory/low-stock", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Data []InventoryItem `json:"data"`
+	}
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
+func (i *InventoryClient) Reserve(inventoryID string, quantity int, orderID string, opts ...RequestOption) error {
+	expiresAt := time.Now().Add(24 * time.Hour).Format(time.RFC3339)
+
+	body := map[string]interface{}{
+		"quantity":   quantity,
+		"order_id":   orderID,
+		"expires_at": expiresAt,
+	}
+
+	resp, err := i.core.doRequest("POST", "/inventory/"+inventoryID+"/reserve", body, opts...)
+	if err != nil {
+		return err
+	}
+
+	return parseResponse(resp, nil)
+}
+
+// adjustOne applies a single quantity adjustment; BatchAdjust uses it as its
+// local fan-out fallback.
+func (i *InventoryClient) adjustOne(a InventoryAdjustment, opts ...RequestOption) (*InventoryItem, error) {
+	body := map[string]interface{}{
+		"delta":  a.Delta,
+		"reason": a.Reason,
+	}
+
+	resp, err := i.core.doRequest("POST", "/inventory/"+a.InventoryID+"/adjust", body, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var item InventoryItem
+	if err := parseResponse(resp, &item); err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// BatchAdjust applies many quantity adjustments in a single request,
+// falling back to parallel single-item requests (see WithBatchConcurrency)
+// if the server doesn't support the batch endpoint. This is the primary
+// path for warehouse imports and ERP syncs that move thousands of SKUs.
+func (i *InventoryClient) BatchAdjust(adjustments []InventoryAdjustment, opts ...RequestOption) ([]BatchResult[InventoryItem], error) {
+	return doBatch(i.core, "/inventory/batch-adjust", adjustments, i.adjustOne, opts...)
+}
+
+// BatchReserve reserves many inventory items in a single request, falling
+// back to parallel single-item requests (see WithBatchConcurrency) if the
+// server doesn't support the batch endpoint.
+func (i *InventoryClient) BatchReserve(reservations []Reservation, opts ...RequestOption) ([]BatchResult[ReservationResult], error) {
+	return doBatch(i.core, "/inventory/batch-reserve", reservations, func(r Reservation, opts ...RequestOption) (*ReservationResult, error) {
+		if err := i.Reserve(r.InventoryID, r.Quantity, r.OrderID, opts...); err != nil {
+			return nil, err
+		}
+		return &ReservationResult{InventoryID: r.InventoryID, Quantity: r.Quantity, OrderID: r.OrderID}, nil
+	}, opts...)
+}
@@ -0,0 +1,179 @@
+package main
+
+/**
+ * Pagination support for the StateSet API Go client.
+ *
+ * ListFilter encodes the common status/date-range/customer/SKU/sort filters
+ * accepted by every list endpoint into query parameters, and Iterator[T]
+ * walks a list endpoint's PaginatedResponse envelope page by page so callers
+ * don't have to juggle page numbers themselves.
+ */
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// ListFilter narrows and orders a list endpoint's results. Zero-valued
+// fields are omitted from the request.
+type ListFilter struct {
+	Status     string
+	DateFrom   time.Time
+	DateTo     time.Time
+	CustomerID string
+	SKU        string
+	Sort       string
+	PerPage    int
+}
+
+// encode turns the filter into query parameters for page/perPage.
+func (f ListFilter) encode(page, perPage int) url.Values {
+	q := url.Values{}
+	q.Set("page", fmt.Sprintf("%d", page))
+	q.Set("per_page", fmt.Sprintf("%d", perPage))
+
+	if f.Status != "" {
+		q.Set("status", f.Status)
+	}
+	if !f.DateFrom.IsZero() {
+		q.Set("date_from", f.DateFrom.Format(time.RFC3339))
+	}
+	if !f.DateTo.IsZero() {
+		q.Set("date_to", f.DateTo.Format(time.RFC3339))
+	}
+	if f.CustomerID != "" {
+		q.Set("customer_id", f.CustomerID)
+	}
+	if f.SKU != "" {
+		q.Set("sku", f.SKU)
+	}
+	if f.Sort != "" {
+		q.Set("sort", f.Sort)
+	}
+
+	return q
+}
+
+// PaginationMeta is the pagination envelope returned alongside a list
+// endpoint's data.
+type PaginationMeta struct {
+	Page       int `json:"page"`
+	PerPage    int `json:"per_page"`
+	Total      int `json:"total"`
+	TotalPages int `json:"total_pages"`
+}
+
+// paginatedEnvelope is the wire format of a paginated list response.
+type paginatedEnvelope[T any] struct {
+	Data       []T            `json:"data"`
+	Pagination PaginationMeta `json:"pagination"`
+}
+
+const defaultPerPage = 20
+
+// Iterator walks a list endpoint page by page, fetching lazily as the
+// caller advances past the items already buffered.
+type Iterator[T any] struct {
+	fetch   func(ctx context.Context, page, perPage int) ([]T, PaginationMeta, error)
+	perPage int
+
+	page       int
+	totalPages int
+	started    bool
+
+	items   []T
+	idx     int
+	current T
+	err     error
+}
+
+// newIterator builds an Iterator backed by fetch, which retrieves one page
+// at a time.
+func newIterator[T any](filter ListFilter, fetch func(ctx context.Context, page, perPage int) ([]T, PaginationMeta, error)) *Iterator[T] {
+	perPage := filter.PerPage
+	if perPage <= 0 {
+		perPage = defaultPerPage
+	}
+	return &Iterator[T]{fetch: fetch, perPage: perPage, page: 1}
+}
+
+// Next advances the iterator, fetching the next page on demand. It returns
+// false once the list is exhausted or a request fails; check Err to tell
+// the two apart.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+
+	for it.idx >= len(it.items) {
+		if it.started && it.page > it.totalPages {
+			return false
+		}
+
+		items, meta, err := it.fetch(ctx, it.page, it.perPage)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.started = true
+		it.totalPages = meta.TotalPages
+		it.items = items
+		it.idx = 0
+		it.page++
+
+		if len(items) == 0 {
+			return false
+		}
+	}
+
+	it.current = it.items[it.idx]
+	it.idx++
+	return true
+}
+
+// Item returns the element most recently advanced to by Next.
+func (it *Iterator[T]) Item() T {
+	return it.current
+}
+
+// Err returns the first error encountered while paging, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Collect drains the iterator into a slice, stopping after max items (or
+// the whole list, if max is 0).
+func (it *Iterator[T]) Collect(ctx context.Context, max int) ([]T, error) {
+	var out []T
+	for it.Next(ctx) {
+		out = append(out, it.Item())
+		if max > 0 && len(out) >= max {
+			break
+		}
+	}
+	if it.Err() != nil {
+		return out, it.Err()
+	}
+	return out, nil
+}
+
+// fetchPage requests a single page of T from path and decodes its
+// PaginatedResponse envelope.
+func fetchPage[T any](ctx context.Context, core *StateSetClient, path string, filter ListFilter, page, perPage int) ([]T, PaginationMeta, error) {
+	query := filter.encode(page, perPage)
+
+	resp, err := core.doRequest("GET", path+"?"+query.Encode(), nil, WithContext(ctx))
+	if err != nil {
+		return nil, PaginationMeta{}, err
+	}
+
+	var envelope paginatedEnvelope[T]
+	if err := parseResponse(resp, &envelope); err != nil {
+		return nil, PaginationMeta{}, err
+	}
+
+	return envelope.Data, envelope.Pagination, nil
+}
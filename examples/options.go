@@ -0,0 +1,88 @@
+package main
+
+/**
+ * Request options for the StateSet API Go client.
+ *
+ * RequestOption follows the common "functional options" pattern so callers
+ * can tweak a single call (extra headers, a timeout, a context, an
+ * idempotency key) without mutating the shared client.
+ */
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// requestConfig accumulates the effect of any RequestOptions passed to doRequest.
+type requestConfig struct {
+	ctx              context.Context
+	headers          map[string]string
+	timeout          time.Duration
+	idempotencyKey   string
+	batchConcurrency int
+}
+
+// RequestOption customizes a single API call.
+type RequestOption func(*requestConfig)
+
+// WithIdempotencyKey attaches an Idempotency-Key header to the request. If
+// key is omitted, a UUIDv4 is generated once, when WithIdempotencyKey is
+// called, so that passing the returned RequestOption to more than one
+// doRequest call reuses the same key rather than minting a new one each
+// time. A same-process retry using the same key short-circuits and returns
+// the cached response instead of hitting the network again.
+func WithIdempotencyKey(key ...string) RequestOption {
+	resolved := uuid.New().String()
+	if len(key) > 0 && key[0] != "" {
+		resolved = key[0]
+	}
+
+	return func(c *requestConfig) {
+		c.idempotencyKey = resolved
+	}
+}
+
+// WithHeader sets an additional header on the request, overriding any
+// header of the same name the client would otherwise set.
+func WithHeader(key, value string) RequestOption {
+	return func(c *requestConfig) {
+		if c.headers == nil {
+			c.headers = make(map[string]string)
+		}
+		c.headers[key] = value
+	}
+}
+
+// WithTimeout overrides the client's default HTTP timeout for this call only.
+func WithTimeout(d time.Duration) RequestOption {
+	return func(c *requestConfig) {
+		c.timeout = d
+	}
+}
+
+// WithContext attaches a caller-supplied context to the request, e.g. for
+// cancellation or tracing deadlines.
+func WithContext(ctx context.Context) RequestOption {
+	return func(c *requestConfig) {
+		c.ctx = ctx
+	}
+}
+
+// WithBatchConcurrency controls how many single-item requests a Batch* call
+// fans out to in parallel when the server doesn't support its batch
+// endpoint. Defaults to 10 if unset or n <= 0.
+func WithBatchConcurrency(n int) RequestOption {
+	return func(c *requestConfig) {
+		c.batchConcurrency = n
+	}
+}
+
+func newRequestConfig(opts []RequestOption) *requestConfig {
+	cfg := &requestConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
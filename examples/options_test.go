@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// TestWithIdempotencyKeyReusedAcrossCalls proves that a RequestOption value
+// returned by WithIdempotencyKey carries the same resolved key no matter how
+// many times it's applied, so a caller retrying a call with the same option
+// value hits the idempotency cache instead of generating a fresh key (and
+// thus a fresh request) each time.
+func TestWithIdempotencyKeyReusedAcrossCalls(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id":"ord_1"}`))
+	}))
+	defer server.Close()
+
+	client := NewStateSetClient(server.URL)
+	opt := WithIdempotencyKey()
+
+	if _, err := client.doRequest("POST", "/orders", nil, opt); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	if _, err := client.doRequest("POST", "/orders", nil, opt); err != nil {
+		t.Fatalf("second call: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Fatalf("server received %d requests, want 1 (second call should have hit the idempotency cache)", got)
+	}
+}
+
+// TestIdempotencyCacheEvictsOldestOverCapacity proves the idempotency cache
+// is bounded: once more keys than its capacity have been stored, the oldest
+// (least-recently-used) one is evicted rather than kept forever.
+func TestIdempotencyCacheEvictsOldestOverCapacity(t *testing.T) {
+	cache := newIdempotencyCache(2)
+
+	cache.put("a", &cachedResponse{statusCode: 200})
+	cache.put("b", &cachedResponse{statusCode: 200})
+	cache.put("c", &cachedResponse{statusCode: 200})
+
+	if _, ok := cache.get("a"); ok {
+		t.Fatalf("key %q should have been evicted once capacity was exceeded", "a")
+	}
+	if _, ok := cache.get("b"); !ok {
+		t.Fatalf("key %q should still be cached", "b")
+	}
+	if _, ok := cache.get("c"); !ok {
+		t.Fatalf("key %q should still be cached", "c")
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+/**
+ * Orders subclient.
+ */
+
+import "context"
+
+// OrdersClient groups the order management endpoints.
+type OrdersClient struct {
+	core *StateSetClient
+}
+
+// OrderInput is a single item submitted to BatchCreate.
+type OrderInput struct {
+	CustomerID string      `json:"customer_id"`
+	Items      []OrderItem `json:"items"`
+}
+
+func (o *OrdersClient) Create(customerID string, items []OrderItem, opts ...RequestOption) (*Order, error) {
+	body := map[string]interface{}{
+		"customer_id": customerID,
+		"items":       items,
+	}
+
+	resp, err := o.core.doRequest("POST", "/orders", body, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var order Order
+	if err := parseResponse(resp, &order); err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// BatchCreate submits many orders in a single request, falling back to
+// parallel single-item requests (see WithBatchConcurrency) if the server
+// doesn't support the batch endpoint.
+func (o *OrdersClient) BatchCreate(inputs []OrderInput, opts ...RequestOption) ([]BatchResult[Order], error) {
+	return doBatch(o.core, "/orders/batch", inputs, func(in OrderInput, opts ...RequestOption) (*Order, error) {
+		return o.Create(in.CustomerID, in.Items, opts...)
+	}, opts...)
+}
+
+// List returns an iterator over orders matching filter.
+func (o *OrdersClient) List(filter ListFilter) *Iterator[Order] {
+	return newIterator(filter, func(ctx context.Context, page, perPage int) ([]Order, PaginationMeta, error) {
+		return fetchPage[Order](ctx, o.core, "/orders", filter, page, perPage)
+	})
+}
+
+func (o *OrdersClient) Get(orderID string) (*Order, error) {
+	resp, err := o.core.doRequest("GET", "/orders/"+orderID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var order Order
+	if err := parseResponse(resp, &order); err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+func (o *OrdersClient) UpdateStatus(orderID, status, notes string) (*Order, error) {
+	body := map[string]string{
+		"status": status,
+		"notes":  notes,
+	}
+
+	resp, err := o.core.doRequest("PUT", "/orders/"+orderID+"/status", body)
+	if err != nil {
+		return nil, err
+	}
+
+	var order Order
+	if err := parseResponse(resp, &order); err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+func (o *OrdersClient) Cancel(orderID, reason string) (*Order, error) {
+	body := map[string]string{
+		"reason": reason,
+	}
+
+	resp, err := o.core.doRequest("POST", "/orders/"+orderID+"/cancel", body)
+	if err != nil {
+		return nil, err
+	}
+
+	var order Order
+	if err := parseResponse(resp, &order); err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
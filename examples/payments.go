@@ -0,0 +1,132 @@
+package main
+
+/**
+ * Cart payment and checkout flow.
+ *
+ * CreatePaymentIntent/ConfirmPayment/Checkout round-trip through the
+ * StateSet API like every other call; PaymentProvider only selects which
+ * PSP the server routes the intent to, and names the default used when a
+ * caller doesn't set PaymentIntentRequest.Provider.
+ */
+
+// PaymentIntentRequest starts a payment for a cart's current total.
+type PaymentIntentRequest struct {
+	Provider        string  `json:"provider,omitempty"`
+	Amount          float64 `json:"amount"`
+	Currency        string  `json:"currency"`
+	PaymentMethodID string  `json:"payment_method_id,omitempty"`
+}
+
+// PaymentNextAction describes an additional step the front-end must drive
+// before the payment can complete, e.g. a 3-D Secure challenge.
+type PaymentNextAction struct {
+	Type        string `json:"type"`
+	RedirectURL string `json:"redirect_url,omitempty"`
+}
+
+// PaymentIntent tracks a single payment attempt against a cart.
+type PaymentIntent struct {
+	ID           string             `json:"id"`
+	Provider     string             `json:"provider"`
+	Amount       float64            `json:"amount"`
+	Currency     string             `json:"currency"`
+	Status       string             `json:"status"`
+	ClientSecret string             `json:"client_secret"`
+	NextAction   *PaymentNextAction `json:"next_action,omitempty"`
+}
+
+// Address is a postal address, used for shipping at checkout.
+type Address struct {
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	State      string `json:"state,omitempty"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+}
+
+// CheckoutRequest converts a cart to an order.
+type CheckoutRequest struct {
+	PaymentIntentID string  `json:"payment_intent_id,omitempty"`
+	ShippingAddress Address `json:"shipping_address"`
+}
+
+// PaymentProvider names a payment service provider for CreatePaymentIntent.
+// Implement it to plug in a PSP of your own without forking the client.
+type PaymentProvider interface {
+	// Name is the provider identifier sent to the API, e.g. "stripe".
+	Name() string
+}
+
+// StripeProvider is the default PaymentProvider.
+type StripeProvider struct{}
+
+func (StripeProvider) Name() string { return "stripe" }
+
+// AdyenProvider routes payment intents through Adyen.
+type AdyenProvider struct{}
+
+func (AdyenProvider) Name() string { return "adyen" }
+
+// ManualProvider marks a payment as collected outside the API, e.g. for
+// purchase orders or cash-on-delivery.
+type ManualProvider struct{}
+
+func (ManualProvider) Name() string { return "manual" }
+
+// CreatePaymentIntent starts a payment for the cart's current total,
+// defaulting PaymentIntentRequest.Provider to cc.Provider's name if unset.
+func (cc *CartsClient) CreatePaymentIntent(cartID string, req PaymentIntentRequest, opts ...RequestOption) (*PaymentIntent, error) {
+	if req.Provider == "" && cc.Provider != nil {
+		req.Provider = cc.Provider.Name()
+	}
+
+	resp, err := cc.core.doRequest("POST", "/carts/"+cartID+"/payment-intent", req, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var intent PaymentIntent
+	if err := parseResponse(resp, &intent); err != nil {
+		return nil, err
+	}
+
+	return &intent, nil
+}
+
+// ConfirmPayment attaches a payment method to the cart's payment intent and
+// attempts to capture it, returning the intent's updated status (which may
+// still carry a NextAction, e.g. for 3-D Secure).
+func (cc *CartsClient) ConfirmPayment(cartID, paymentMethodID string, opts ...RequestOption) (*PaymentIntent, error) {
+	body := map[string]string{
+		"payment_method_id": paymentMethodID,
+	}
+
+	resp, err := cc.core.doRequest("POST", "/carts/"+cartID+"/confirm-payment", body, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var intent PaymentIntent
+	if err := parseResponse(resp, &intent); err != nil {
+		return nil, err
+	}
+
+	return &intent, nil
+}
+
+// Checkout atomically converts the cart to an order, reserving inventory
+// for each line item.
+func (cc *CartsClient) Checkout(cartID string, req CheckoutRequest, opts ...RequestOption) (*Order, error) {
+	resp, err := cc.core.doRequest("POST", "/carts/"+cartID+"/checkout", req, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var order Order
+	if err := parseResponse(resp, &order); err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
@@ -0,0 +1,46 @@
+package main
+
+/**
+ * Returns subclient.
+ */
+
+// ReturnsClient groups the returns management endpoints.
+type ReturnsClient struct {
+	core *StateSetClient
+}
+
+func (r *ReturnsClient) Create(orderID string, items []ReturnItem, notes string, opts ...RequestOption) (*Return, error) {
+	body := map[string]interface{}{
+		"order_id": orderID,
+		"items":    items,
+	}
+	if notes != "" {
+		body["customer_notes"] = notes
+	}
+
+	resp, err := r.core.doRequest("POST", "/returns", body, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var returnObj Return
+	if err := parseResponse(resp, &returnObj); err != nil {
+		return nil, err
+	}
+
+	return &returnObj, nil
+}
+
+func (r *ReturnsClient) Approve(returnID string) (*Return, error) {
+	resp, err := r.core.doRequest("POST", "/returns/"+returnID+"/approve", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var returnObj Return
+	if err := parseResponse(resp, &returnObj); err != nil {
+		return nil, err
+	}
+
+	return &returnObj, nil
+}
@@ -0,0 +1,81 @@
+package main
+
+/**
+ * Shipments subclient.
+ */
+
+import "time"
+
+// ShipmentsClient groups the shipment management endpoints.
+type ShipmentsClient struct {
+	core *StateSetClient
+}
+
+// ShipmentInput is a single item submitted to BatchCreate.
+type ShipmentInput struct {
+	OrderID      string `json:"order_id"`
+	Carrier      string `json:"carrier"`
+	ServiceLevel string `json:"service_level"`
+}
+
+func (s *ShipmentsClient) Create(orderID, carrier, serviceLevel string, opts ...RequestOption) (*Shipment, error) {
+	body := map[string]string{
+		"order_id":      orderID,
+		"carrier":       carrier,
+		"service_level": serviceLevel,
+	}
+
+	resp, err := s.core.doRequest("POST", "/shipments", body, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var shipment Shipment
+	if err := parseResponse(resp, &shipment); err != nil {
+		return nil, err
+	}
+
+	return &shipment, nil
+}
+
+// BatchCreate submits many shipments in a single request, falling back to
+// parallel single-item requests (see WithBatchConcurrency) if the server
+// doesn't support the batch endpoint.
+func (s *ShipmentsClient) BatchCreate(inputs []ShipmentInput, opts ...RequestOption) ([]BatchResult[Shipment], error) {
+	return doBatch(s.core, "/shipments/batch", inputs, func(in ShipmentInput, opts ...RequestOption) (*Shipment, error) {
+		return s.Create(in.OrderID, in.Carrier, in.ServiceLevel, opts...)
+	}, opts...)
+}
+
+func (s *ShipmentsClient) MarkAsShipped(shipmentID, trackingNumber string) (*Shipment, error) {
+	body := map[string]string{
+		"tracking_number": trackingNumber,
+		"shipped_at":      time.Now().Format(time.RFC3339),
+	}
+
+	resp, err := s.core.doRequest("POST", "/shipments/"+shipmentID+"/ship", body)
+	if err != nil {
+		return nil, err
+	}
+
+	var shipment Shipment
+	if err := parseResponse(resp, &shipment); err != nil {
+		return nil, err
+	}
+
+	return &shipment, nil
+}
+
+func (s *ShipmentsClient) Track(trackingNumber string) (map[string]interface{}, error) {
+	resp, err := s.core.doRequest("GET", "/shipments/track/"+trackingNumber, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]interface{}
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
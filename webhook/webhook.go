@@ -0,0 +1,295 @@
+package webhook
+
+/**
+ * StateSet API - Webhook Receiver
+ *
+ * Verifies and dispatches webhook deliveries for shipment, return, order,
+ * inventory, and payment events pushed by the StateSet API (rather than
+ * polled for), as used by 3PL and carrier integrations.
+ *
+ * Installation:
+ * go get github.com/stateset/stateset-api/webhook
+ */
+
+import (
+	"container/list"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxTimestampSkew is how far X-StateSet-Timestamp may drift from now
+// before a delivery is rejected as a possible replay.
+const maxTimestampSkew = 5 * time.Minute
+
+// Envelope is the wire format of every webhook delivery.
+type Envelope struct {
+	EventID   string          `json:"event_id"`
+	EventType string          `json:"event_type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// ShipmentUpdated fires whenever a shipment's status or tracking changes.
+type ShipmentUpdated struct {
+	ShipmentID     string `json:"shipment_id"`
+	OrderID        string `json:"order_id"`
+	Status         string `json:"status"`
+	TrackingNumber string `json:"tracking_number,omitempty"`
+}
+
+// ShipmentDelivered fires once a carrier confirms final delivery.
+type ShipmentDelivered struct {
+	ShipmentID  string    `json:"shipment_id"`
+	OrderID     string    `json:"order_id"`
+	DeliveredAt time.Time `json:"delivered_at"`
+}
+
+// ReturnApproved fires when a return request is approved for processing.
+type ReturnApproved struct {
+	ReturnID string `json:"return_id"`
+	OrderID  string `json:"order_id"`
+}
+
+// OrderStatusChanged fires on any order status transition.
+type OrderStatusChanged struct {
+	OrderID   string `json:"order_id"`
+	OldStatus string `json:"old_status"`
+	NewStatus string `json:"new_status"`
+}
+
+// InventoryLowStock fires when an inventory item crosses its reorder threshold.
+type InventoryLowStock struct {
+	InventoryID       string `json:"inventory_id"`
+	SKU               string `json:"sku"`
+	QuantityAvailable int    `json:"quantity_available"`
+}
+
+// PaymentCaptured fires once a cart's payment intent is successfully captured.
+type PaymentCaptured struct {
+	PaymentIntentID string  `json:"payment_intent_id"`
+	CartID          string  `json:"cart_id"`
+	Amount          float64 `json:"amount"`
+	Currency        string  `json:"currency"`
+}
+
+// SeenStore deduplicates webhook deliveries by event ID. Implementations
+// must be safe for concurrent use.
+type SeenStore interface {
+	// Seen reports whether eventID has already been marked done. It does
+	// not itself mark anything, so a delivery can be checked before it's
+	// processed and only marked once processing actually succeeds.
+	Seen(eventID string) bool
+	// MarkSeen marks eventID as done, so a later Seen call returns true.
+	MarkSeen(eventID string)
+}
+
+// Handler verifies, deduplicates, and dispatches incoming webhook deliveries.
+type Handler struct {
+	secret string
+	store  SeenStore
+
+	onShipmentUpdated   func(ShipmentUpdated)
+	onShipmentDelivered func(ShipmentDelivered)
+	onReturnApproved    func(ReturnApproved)
+	onOrderStatusChange func(OrderStatusChanged)
+	onInventoryLowStock func(InventoryLowStock)
+	onPaymentCaptured   func(PaymentCaptured)
+}
+
+// NewHandler creates a Handler that verifies deliveries against secret. It
+// defaults to a 10,000-entry in-memory LRU SeenStore; pass WithSeenStore to
+// use a shared store across replicas.
+func NewHandler(secret string, opts ...HandlerOption) *Handler {
+	h := &Handler{
+		secret: secret,
+		store:  newLRUSeenStore(10_000),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// HandlerOption customizes a Handler.
+type HandlerOption func(*Handler)
+
+// WithSeenStore overrides the default in-memory LRU dedup store, e.g. with
+// one backed by Redis so multiple replicas share delivery state.
+func WithSeenStore(store SeenStore) HandlerOption {
+	return func(h *Handler) {
+		h.store = store
+	}
+}
+
+func (h *Handler) OnShipmentUpdated(fn func(ShipmentUpdated))     { h.onShipmentUpdated = fn }
+func (h *Handler) OnShipmentDelivered(fn func(ShipmentDelivered)) { h.onShipmentDelivered = fn }
+func (h *Handler) OnReturnApproved(fn func(ReturnApproved))       { h.onReturnApproved = fn }
+func (h *Handler) OnOrderStatusChanged(fn func(OrderStatusChanged)) {
+	h.onOrderStatusChange = fn
+}
+func (h *Handler) OnInventoryLowStock(fn func(InventoryLowStock)) { h.onInventoryLowStock = fn }
+func (h *Handler) OnPaymentCaptured(fn func(PaymentCaptured))     { h.onPaymentCaptured = fn }
+
+// ServeHTTP verifies the delivery's signature and timestamp, deduplicates
+// on X-StateSet-Event-ID, and dispatches to the matching On* callback.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	timestamp := r.Header.Get("X-StateSet-Timestamp")
+	if err := checkTimestamp(timestamp); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	signature := r.Header.Get("X-StateSet-Signature")
+	if !hmac.Equal([]byte(signature), []byte(MustSign(h.secret, body, timestamp))) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	eventID := r.Header.Get("X-StateSet-Event-ID")
+	if eventID == "" {
+		http.Error(w, "missing X-StateSet-Event-ID", http.StatusBadRequest)
+		return
+	}
+	if h.store.Seen(eventID) {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var envelope Envelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.dispatch(envelope); err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	// Only mark the delivery done now that it's actually been processed, so
+	// a 422 (which is supposed to prompt the sender to retry) doesn't get
+	// treated as a duplicate and silently dropped on the retry.
+	h.store.MarkSeen(eventID)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) dispatch(envelope Envelope) error {
+	switch envelope.EventType {
+	case "shipment.updated":
+		return decodeAndDispatch(envelope.Data, h.onShipmentUpdated)
+	case "shipment.delivered":
+		return decodeAndDispatch(envelope.Data, h.onShipmentDelivered)
+	case "return.approved":
+		return decodeAndDispatch(envelope.Data, h.onReturnApproved)
+	case "order.status_changed":
+		return decodeAndDispatch(envelope.Data, h.onOrderStatusChange)
+	case "inventory.low_stock":
+		return decodeAndDispatch(envelope.Data, h.onInventoryLowStock)
+	case "payment.captured":
+		return decodeAndDispatch(envelope.Data, h.onPaymentCaptured)
+	default:
+		return nil
+	}
+}
+
+// decodeAndDispatch decodes data into T and invokes fn, if the caller
+// registered a callback for this event type.
+func decodeAndDispatch[T any](data json.RawMessage, fn func(T)) error {
+	if fn == nil {
+		return nil
+	}
+	var event T
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to decode event payload: %w", err)
+	}
+	fn(event)
+	return nil
+}
+
+func checkTimestamp(raw string) error {
+	if raw == "" {
+		return fmt.Errorf("missing X-StateSet-Timestamp")
+	}
+	seconds, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid X-StateSet-Timestamp")
+	}
+	skew := time.Since(time.Unix(seconds, 0))
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > maxTimestampSkew {
+		return fmt.Errorf("timestamp outside of allowed skew")
+	}
+	return nil
+}
+
+// MustSign computes the HMAC-SHA256(secret, timestamp+body) signature a
+// genuine StateSet delivery would carry in X-StateSet-Signature. It's
+// exported so tests can construct valid deliveries without a live sender.
+func MustSign(secret string, body []byte, timestamp string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// lruSeenStore is the default in-memory SeenStore, evicting the
+// least-recently-marked event ID once it exceeds capacity.
+type lruSeenStore struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	index    map[string]*list.Element
+}
+
+func newLRUSeenStore(capacity int) *lruSeenStore {
+	return &lruSeenStore{
+		capacity: capacity,
+		order:    list.New(),
+		index:    make(map[string]*list.Element),
+	}
+}
+
+func (s *lruSeenStore) Seen(eventID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.index[eventID]
+	return ok
+}
+
+func (s *lruSeenStore) MarkSeen(eventID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.index[eventID]; ok {
+		s.order.MoveToFront(elem)
+		return
+	}
+
+	elem := s.order.PushFront(eventID)
+	s.index[eventID] = elem
+
+	if s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		if oldest != nil {
+			s.order.Remove(oldest)
+			delete(s.index, oldest.Value.(string))
+		}
+	}
+}
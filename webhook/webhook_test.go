@@ -0,0 +1,126 @@
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestHandlerDispatchesVerifiedDelivery drives a Handler end-to-end: sign a
+// delivery the way the StateSet API would, POST it to the handler's
+// ServeHTTP, and confirm the matching On* callback fires exactly once even
+// if the same delivery is replayed.
+func TestHandlerDispatchesVerifiedDelivery(t *testing.T) {
+	const secret = "whsec_test"
+
+	var got ShipmentUpdated
+	calls := 0
+	h := NewHandler(secret)
+	h.OnShipmentUpdated(func(e ShipmentUpdated) {
+		calls++
+		got = e
+	})
+
+	body := []byte(`{"event_id":"evt_1","event_type":"shipment.updated","data":{"shipment_id":"shp_1","order_id":"ord_1","status":"in_transit"}}`)
+	timestamp := fmt.Sprintf("%d", time.Now().Unix())
+	signature := MustSign(secret, body, timestamp)
+
+	deliver := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/stateset", bytes.NewReader(body))
+		req.Header.Set("X-StateSet-Timestamp", timestamp)
+		req.Header.Set("X-StateSet-Signature", signature)
+		req.Header.Set("X-StateSet-Event-ID", "evt_1")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := deliver(); rec.Code != http.StatusOK {
+		t.Fatalf("first delivery: got status %d, want 200", rec.Code)
+	}
+	if calls != 1 {
+		t.Fatalf("got %d callback invocations, want 1", calls)
+	}
+	if got.ShipmentID != "shp_1" || got.Status != "in_transit" {
+		t.Fatalf("unexpected event: %+v", got)
+	}
+
+	// A replayed delivery with the same event ID must be deduplicated, not
+	// dispatched again.
+	if rec := deliver(); rec.Code != http.StatusOK {
+		t.Fatalf("replayed delivery: got status %d, want 200", rec.Code)
+	}
+	if calls != 1 {
+		t.Fatalf("got %d callback invocations after replay, want 1", calls)
+	}
+}
+
+// TestHandlerRetriesAfterFailedDispatch confirms a delivery that fails to
+// dispatch (422) is not marked seen, so a verbatim retry is processed
+// instead of being silently treated as a duplicate.
+func TestHandlerRetriesAfterFailedDispatch(t *testing.T) {
+	const secret = "whsec_test"
+
+	calls := 0
+	h := NewHandler(secret)
+	h.OnShipmentUpdated(func(ShipmentUpdated) { calls++ })
+
+	// data is not a valid ShipmentUpdated payload, so dispatch fails.
+	body := []byte(`{"event_id":"evt_3","event_type":"shipment.updated","data":"not-an-object"}`)
+	timestamp := fmt.Sprintf("%d", time.Now().Unix())
+	signature := MustSign(secret, body, timestamp)
+
+	deliver := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/stateset", bytes.NewReader(body))
+		req.Header.Set("X-StateSet-Timestamp", timestamp)
+		req.Header.Set("X-StateSet-Signature", signature)
+		req.Header.Set("X-StateSet-Event-ID", "evt_3")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := deliver(); rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("first delivery: got status %d, want 422", rec.Code)
+	}
+
+	// Fix the payload and retry with the same event ID, as a real sender
+	// would after seeing a 422.
+	body = []byte(`{"event_id":"evt_3","event_type":"shipment.updated","data":{"shipment_id":"shp_3","order_id":"ord_3","status":"in_transit"}}`)
+	signature = MustSign(secret, body, timestamp)
+
+	if rec := deliver(); rec.Code != http.StatusOK {
+		t.Fatalf("retried delivery: got status %d, want 200", rec.Code)
+	}
+	if calls != 1 {
+		t.Fatalf("got %d callback invocations after retry, want 1", calls)
+	}
+}
+
+// TestHandlerRejectsBadSignature confirms a delivery signed with the wrong
+// secret is rejected before it ever reaches a callback.
+func TestHandlerRejectsBadSignature(t *testing.T) {
+	calls := 0
+	h := NewHandler("whsec_test")
+	h.OnShipmentUpdated(func(ShipmentUpdated) { calls++ })
+
+	body := []byte(`{"event_id":"evt_2","event_type":"shipment.updated","data":{}}`)
+	timestamp := fmt.Sprintf("%d", time.Now().Unix())
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/stateset", bytes.NewReader(body))
+	req.Header.Set("X-StateSet-Timestamp", timestamp)
+	req.Header.Set("X-StateSet-Signature", MustSign("wrong-secret", body, timestamp))
+	req.Header.Set("X-StateSet-Event-ID", "evt_2")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("got status %d, want 401", rec.Code)
+	}
+	if calls != 0 {
+		t.Fatalf("got %d callback invocations, want 0", calls)
+	}
+}